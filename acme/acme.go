@@ -0,0 +1,222 @@
+// Package acme implements go-acme/lego's challenge.Provider interface on top
+// of the hover package, so that hover.Client can be used to solve ACME dns-01
+// challenges. It has no dependency on lego itself: DNSProvider satisfies
+// challenge.Provider (Present/CleanUp/Timeout) structurally, and the tiny
+// bit of dns-01 logic this package needs (the challenge FQDN and key
+// authorization digest) is reimplemented below rather than pulling in all of
+// lego/v4, whose go.mod drags in dozens of unrelated cloud-provider SDKs.
+package acme
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/jmhodges/hover"
+)
+
+// DNSProvider implements the lego challenge.Provider interface using a
+// hover.Client to add and remove the TXT records dns-01 challenges need.
+type DNSProvider struct {
+	client *hover.Client
+
+	propagationTimeout time.Duration
+	pollingInterval    time.Duration
+	ttl                time.Duration
+
+	mu      sync.Mutex
+	records map[string]hover.DNSRecordID
+}
+
+// Config holds the configuration used by NewDNSProviderConfig to build a
+// DNSProvider.
+type Config struct {
+	// HTTPClient is the *http.Client used to log into Hover. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Cookie is an existing "hoverauth" login cookie. If set, Username and
+	// Password are ignored.
+	Cookie *http.Cookie
+	// Username and Password are the Hover account credentials used to log in
+	// when Cookie isn't set.
+	Username string
+	Password string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                time.Duration
+}
+
+const (
+	defaultPropagationTimeout = 2 * time.Minute
+	defaultPollingInterval    = 4 * time.Second
+	defaultTTL                = 5 * time.Minute
+)
+
+// NewDNSProviderConfig builds a *DNSProvider from the given Config, logging
+// into Hover if Config.Cookie isn't already set.
+func NewDNSProviderConfig(ctx context.Context, cfg *Config) (*DNSProvider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("hover: the configuration of the DNS provider is nil")
+	}
+	hc := cfg.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	cook := cfg.Cookie
+	if cook == nil {
+		if cfg.Username == "" || cfg.Password == "" {
+			return nil, fmt.Errorf("hover: Username and Password are required when Cookie isn't set")
+		}
+		var err error
+		cook, err = hover.Login(ctx, hc, cfg.Username, cfg.Password)
+		if err != nil {
+			return nil, fmt.Errorf("hover: %s", err)
+		}
+	}
+
+	propTimeout := cfg.PropagationTimeout
+	if propTimeout == 0 {
+		propTimeout = defaultPropagationTimeout
+	}
+	pollInterval := cfg.PollingInterval
+	if pollInterval == 0 {
+		pollInterval = defaultPollingInterval
+	}
+	ttl := cfg.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	return &DNSProvider{
+		client:             hover.NewClient(hc, cook),
+		propagationTimeout: propTimeout,
+		pollingInterval:    pollInterval,
+		ttl:                ttl,
+		records:            make(map[string]hover.DNSRecordID),
+	}, nil
+}
+
+// Present adds a TXT record to fulfil the dns-01 challenge for domain.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
+	fqdn, value := challengeRecord(domain, keyAuth)
+
+	domainID, sub, err := d.findDomain(ctx, fqdn)
+	if err != nil {
+		return fmt.Errorf("hover: %s", err)
+	}
+
+	rec := &hover.NewDNSRecord{
+		Type:    hover.TXT,
+		Name:    sub,
+		Content: value,
+		TTL:     d.ttl,
+	}
+	if err := d.client.AddDNSRecord(ctx, domainID, rec); err != nil {
+		return fmt.Errorf("hover: %s", err)
+	}
+
+	dnsDomains, err := d.client.GetDNSDomains(ctx, domainID)
+	if err != nil {
+		return fmt.Errorf("hover: %s", err)
+	}
+	var id hover.DNSRecordID
+	for _, dnsDomain := range dnsDomains {
+		for _, entry := range dnsDomain.Entries {
+			if entry.Type == hover.TXT && entry.Name == sub && entry.Content == value {
+				id = entry.ID
+			}
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("hover: unable to find the TXT record just added for %s", fqdn)
+	}
+
+	d.mu.Lock()
+	d.records[recordKey(fqdn, value)] = id
+	d.mu.Unlock()
+	return nil
+}
+
+// CleanUp removes the TXT record Present added for domain.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+	fqdn, value := challengeRecord(domain, keyAuth)
+
+	key := recordKey(fqdn, value)
+	d.mu.Lock()
+	id, ok := d.records[key]
+	delete(d.records, key)
+	d.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("hover: no TXT record tracked for %s", fqdn)
+	}
+
+	if err := d.client.DeleteDNSRecord(ctx, id); err != nil {
+		return fmt.Errorf("hover: %s", err)
+	}
+	return nil
+}
+
+// Timeout returns the propagation timeout and polling interval lego should
+// use while waiting for the TXT record added by Present to become visible.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.propagationTimeout, d.pollingInterval
+}
+
+// findDomain locates the Domain that owns fqdn and returns its DomainID along
+// with the subdomain portion of fqdn relative to that domain. Rather than
+// resolving the authoritative zone over DNS (as lego's dns01.FindZoneByFqdn
+// does), it matches directly against the account's own Hover domain list,
+// which is both simpler and more reliable: Hover is the registrar of record
+// for any domain this provider can actually update.
+func (d *DNSProvider) findDomain(ctx context.Context, fqdn string) (hover.DomainID, string, error) {
+	domains, err := d.client.Domains(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	name := unFqdn(fqdn)
+
+	var best *hover.Domain
+	for i, dom := range domains {
+		if name != dom.DomainName && !strings.HasSuffix(name, "."+dom.DomainName) {
+			continue
+		}
+		if best == nil || len(dom.DomainName) > len(best.DomainName) {
+			best = domains[i]
+		}
+	}
+	if best == nil {
+		return "", "", fmt.Errorf("no Hover domain found that owns %s", fqdn)
+	}
+	sub := strings.TrimSuffix(name, best.DomainName)
+	sub = strings.TrimSuffix(sub, ".")
+	return best.ID, sub, nil
+}
+
+// challengeRecord computes the dns-01 challenge FQDN and TXT record value
+// for domain and keyAuth, per RFC 8555 section 8.4: the value is the
+// base64url (no padding) of the SHA-256 digest of the key authorization, and
+// the FQDN is "_acme-challenge." prepended to domain.
+func challengeRecord(domain, keyAuth string) (fqdn, value string) {
+	digest := sha256.Sum256([]byte(keyAuth))
+	value = base64.RawURLEncoding.EncodeToString(digest[:])
+	fqdn = "_acme-challenge." + unFqdn(domain) + "."
+	return fqdn, value
+}
+
+// unFqdn strips a single trailing dot from s, if present.
+func unFqdn(s string) string {
+	return strings.TrimSuffix(s, ".")
+}
+
+func recordKey(fqdn, value string) string {
+	return fqdn + "|" + value
+}
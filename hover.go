@@ -8,11 +8,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/jmhodges/hover/zonefile"
 )
 
 // RecordType is a string of DNS record type's name. See the constants of this
@@ -30,6 +34,11 @@ func (rt *RecordType) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON is for making it easy to JSON encode a RecordType
+func (rt RecordType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(rt))
+}
+
 // Some of the available DNS record types.
 const (
 	A     = RecordType("A")
@@ -45,8 +54,9 @@ const defaultURL = "https://www.hover.com/api"
 
 // Client provides methods to access the unofficial Hover DNS API
 type Client struct {
-	hc   *http.Client
-	cook *http.Cookie
+	hc    *http.Client
+	cook  *http.Cookie
+	retry *RetryPolicy
 }
 
 // InvalidLogin is returned from Login when the credentials don't work
@@ -58,31 +68,175 @@ func (il InvalidLogin) Error() string {
 
 // Login takes a Hover username and password and returns the login cookie value
 func Login(ctx context.Context, hc *http.Client, username, password string) (*http.Cookie, error) {
+	return LoginWith2FA(ctx, hc, username, password, nil)
+}
+
+// twoFAResp is the subset of the /login response body used to detect that
+// Hover wants a second authentication factor before it will issue the
+// hoverauth cookie.
+type twoFAResp struct {
+	Status string `json:"status"`
+}
+
+const status2FARequired = "need_2fa"
+
+// LoginWith2FA takes a Hover username and password and returns the login
+// cookie value, like Login, but also handles accounts that have two-factor
+// authentication enabled. If Hover's response to the initial credentials
+// indicates a second factor is required, codeProvider is called to obtain a
+// TOTP or SMS code, which is then submitted to complete the login.
+// codeProvider may be nil, in which case a 2FA challenge is reported as an
+// InvalidLogin rather than handled.
+func LoginWith2FA(ctx context.Context, hc *http.Client, username, password string, codeProvider func(ctx context.Context) (string, error)) (*http.Cookie, error) {
 	v := make(url.Values)
 	v.Set("username", username)
 	v.Set("password", password)
 
 	r, err := ctxhttp.Get(ctx, hc, fmt.Sprintf("%s/login?%s", defaultURL, v.Encode()))
-
 	if err != nil {
 		return nil, err
 	}
+	defer r.Body.Close()
 	if r.StatusCode != 200 {
 		return nil, InvalidLogin(fmt.Sprintf("login HTTP status code was %d", r.StatusCode))
 	}
-	var c *http.Cookie
+	if c := authCookie(r.Cookies()); c != nil {
+		return c, nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var tfa twoFAResp
+	if err := json.Unmarshal(body, &tfa); err != nil || tfa.Status != status2FARequired {
+		return nil, InvalidLogin("unable to find 'hoverauth' cookie with data in response")
+	}
+	if codeProvider == nil {
+		return nil, InvalidLogin("account requires two-factor authentication but no codeProvider was given")
+	}
+	code, err := codeProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cv := make(url.Values)
+	cv.Set("code", code)
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/login/2fa", defaultURL), strings.NewReader(cv.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	for _, cook := range r.Cookies() {
-		if cook.Name == "hoverauth" && cook.Value != "" {
-			c = cook
-			break
-		}
+		req.AddCookie(cook)
+	}
+	r2, err := ctxhttp.Do(ctx, hc, req)
+	if err != nil {
+		return nil, err
+	}
+	defer r2.Body.Close()
+	if r2.StatusCode != 200 {
+		return nil, InvalidLogin(fmt.Sprintf("2fa HTTP status code was %d", r2.StatusCode))
 	}
+	c := authCookie(r2.Cookies())
 	if c == nil {
-		return nil, InvalidLogin("unable to find 'hoverauth' cookie with data in response")
+		return nil, InvalidLogin("unable to find 'hoverauth' cookie with data in the response to the 2fa code")
 	}
 	return c, nil
 }
 
+// authCookie returns the "hoverauth" cookie among cookies, or nil if it
+// isn't present or is empty.
+func authCookie(cookies []*http.Cookie) *http.Cookie {
+	for _, cook := range cookies {
+		if cook.Name == "hoverauth" && cook.Value != "" {
+			return cook
+		}
+	}
+	return nil
+}
+
+// CookieStore persists a Hover login cookie between process runs, so that
+// long-running callers like cert renewal daemons or dynamic-DNS agents don't
+// need to submit credentials (and solve any 2FA challenge) on every restart.
+type CookieStore interface {
+	// Load returns the previously saved cookie, or nil if none is stored.
+	Load() (*http.Cookie, error)
+	// Save persists cook for later Load calls.
+	Save(cook *http.Cookie) error
+}
+
+// FileCookieStore is a CookieStore that persists the cookie as JSON in a
+// single file at Path.
+type FileCookieStore struct {
+	Path string
+}
+
+// NewFileCookieStore returns a FileCookieStore backed by the file at path.
+// The file need not exist yet; Load returns a nil cookie until Save is
+// called.
+func NewFileCookieStore(path string) *FileCookieStore {
+	return &FileCookieStore{Path: path}
+}
+
+// Load implements CookieStore.
+func (f *FileCookieStore) Load() (*http.Cookie, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cook http.Cookie
+	if err := json.Unmarshal(b, &cook); err != nil {
+		return nil, err
+	}
+	return &cook, nil
+}
+
+// Save implements CookieStore.
+func (f *FileCookieStore) Save(cook *http.Cookie) error {
+	b, err := json.Marshal(cook)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, b, 0600)
+}
+
+// NewClientFromStore builds a Client using the cookie saved in store, if
+// any, verifying it still works with a cheap API call. If store has no
+// cookie yet, or the saved one has expired, NewClientFromStore logs in with
+// username and password (using LoginWith2FA if codeProvider is non-nil) and
+// saves the fresh cookie back to store, so that long-running callers
+// re-authenticate transparently instead of failing on every restart.
+func NewClientFromStore(ctx context.Context, hc *http.Client, store CookieStore, username, password string, codeProvider func(ctx context.Context) (string, error)) (*Client, error) {
+	cook, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cook != nil {
+		c := NewClient(hc, cook)
+		if _, err := c.Domains(ctx); err == nil {
+			return c, nil
+		}
+	}
+
+	var fresh *http.Cookie
+	if codeProvider != nil {
+		fresh, err = LoginWith2FA(ctx, hc, username, password, codeProvider)
+	} else {
+		fresh, err = Login(ctx, hc, username, password)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Save(fresh); err != nil {
+		return nil, err
+	}
+	return NewClient(hc, fresh), nil
+}
+
 // NewClient takes an http.Client with the hoverauth cookie in its cookiejar
 func NewClient(hc *http.Client, loginCookie *http.Cookie) *Client {
 	return &Client{hc: hc, cook: loginCookie}
@@ -178,6 +332,11 @@ func (dri *DomainID) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON is for making it easy to JSON encode a DomainID
+func (dri DomainID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(dri))
+}
+
 // DNSRecordID is a string that identifies a specific DNSRecord in a DNSDomain
 type DNSRecordID string
 
@@ -192,6 +351,11 @@ func (dri *DNSRecordID) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON is for making it easy to JSON encode a DNSRecordID
+func (dri DNSRecordID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(dri))
+}
+
 // DNSRecord represents a DNS record for a given DNSDomain
 type DNSRecord struct {
 	ID        DNSRecordID `json:"id,omitempty"`
@@ -225,21 +389,48 @@ func (ttl *TTL) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON is for making it easy to JSON encode a TTL back into the
+// integer number of seconds the API expects.
+func (ttl TTL) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(time.Duration(ttl) / time.Second))), nil
+}
+
 // Date is for parsing year, month, and day date strings.
 type Date struct {
 	time.Time
 }
 
-// UnmarshalJSON is for making it easy to JSON parse Date
+// UnmarshalJSON is for making it easy to JSON parse Date. A JSON null or an
+// empty string decodes to the zero Date, since Hover returns those for
+// domains that don't have the given date set yet.
 func (d *Date) UnmarshalJSON(b []byte) error {
-	if b[0] == '"' && b[len(b)-1] == '"' {
-		b = b[1 : len(b)-1]
-	} else {
+	if string(b) == "null" {
+		d.Time = time.Time{}
+		return nil
+	}
+	if b[0] != '"' || b[len(b)-1] != '"' {
 		return fmt.Errorf("unable to parse a Date from %#v", string(b))
 	}
-	var err error
-	d.Time, err = time.Parse("2006-01-02", string(b))
-	return fmt.Errorf("unable to parse the Date string: %s", err)
+	s := string(b[1 : len(b)-1])
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("unable to parse the Date string: %s", err)
+	}
+	d.Time = t
+	return nil
+}
+
+// MarshalJSON is for making it easy to JSON encode a Date. The zero Date
+// encodes as JSON null.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + d.Time.Format("2006-01-02") + `"`), nil
 }
 
 // YearMonth is for parsing year and month date strings. Used only in the
@@ -248,18 +439,49 @@ type YearMonth struct {
 	time.Time
 }
 
-// UnmarshalJSON is for making it easy to JSON parse YearMonth
+// UnmarshalJSON is for making it easy to JSON parse YearMonth. A JSON null
+// or an empty string decodes to the zero YearMonth, since Hover returns
+// those for domains that don't have the given date set yet.
 func (ym *YearMonth) UnmarshalJSON(b []byte) error {
-	if b[0] == '"' && b[len(b)-1] == '"' {
-		b = b[1 : len(b)-1]
-	} else {
+	if string(b) == "null" {
+		ym.Time = time.Time{}
+		return nil
+	}
+	if b[0] != '"' || b[len(b)-1] != '"' {
 		return fmt.Errorf("unable to parse a YearMonth from %#v", string(b))
 	}
-	var err error
-	ym.Time, err = time.Parse("2006/01", string(b))
-	return fmt.Errorf("unable to parse the YearMonth string: %s", err)
+	s := string(b[1 : len(b)-1])
+	if s == "" {
+		ym.Time = time.Time{}
+		return nil
+	}
+	t, err := time.Parse("2006/01", s)
+	if err != nil {
+		return fmt.Errorf("unable to parse the YearMonth string: %s", err)
+	}
+	ym.Time = t
+	return nil
+}
+
+// MarshalJSON is for making it easy to JSON encode a YearMonth. The zero
+// YearMonth encodes as JSON null.
+func (ym YearMonth) MarshalJSON() ([]byte, error) {
+	if ym.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + ym.Time.Format("2006/01") + `"`), nil
 }
 
+// Sentinel errors that an APIError matches via errors.Is, based on the HTTP
+// status code of the response that produced it. Callers should test for
+// these instead of string-matching APIError.ErrorMsg.
+var (
+	ErrNotFound     = errors.New("hover: resource not found")
+	ErrUnauthorized = errors.New("hover: unauthorized")
+	ErrRateLimited  = errors.New("hover: rate limited")
+	ErrConflict     = errors.New("hover: conflict")
+)
+
 // APIError is the error type used for errors from the Hover API.
 type APIError struct {
 	// StatusCode is the HTTP status code
@@ -268,6 +490,10 @@ type APIError struct {
 	ErrorCode string
 	// ErrorMsg is the the JSON error_message field
 	ErrorMsg string
+	// RetryAfter is how long the server asked the caller to wait before
+	// retrying, parsed from the response's Retry-After header. It is zero
+	// if the response didn't include one.
+	RetryAfter time.Duration
 }
 
 // Error allows APIError to match the error interface type
@@ -275,6 +501,53 @@ func (ae *APIError) Error() string {
 	return fmt.Sprintf("Hover API returned error code %#v: %s", ae.ErrorCode, ae.ErrorMsg)
 }
 
+// Is lets errors.Is(err, ErrNotFound) and friends match an APIError based on
+// its StatusCode, without every caller needing to know the field to check.
+func (ae *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return ae.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return ae.StatusCode == http.StatusUnauthorized || ae.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return ae.StatusCode == http.StatusTooManyRequests
+	case ErrConflict:
+		return ae.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// RetryPolicy controls how a Client automatically retries idempotent GET and
+// DELETE requests that fail with a 429 or 5xx response. A Client with no
+// RetryPolicy set (the default) never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times to try the request,
+	// including the first attempt. A value of 1 or less disables retries.
+	MaxAttempts int
+	// Backoff computes how long to wait before the next attempt, given the
+	// attempt number (1 for the first retry, 2 for the second, and so on)
+	// and any Retry-After duration the server asked for, which is zero if
+	// none was sent. If Backoff is nil, ExponentialBackoff is used.
+	Backoff func(attempt int, retryAfter time.Duration) time.Duration
+}
+
+// ExponentialBackoff is the default RetryPolicy.Backoff. It honors the
+// server's Retry-After if one was given, and otherwise waits 2^attempt
+// seconds.
+func ExponentialBackoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	return time.Duration(1<<uint(attempt)) * time.Second
+}
+
+// SetRetryPolicy configures c to automatically retry idempotent GET and
+// DELETE requests that fail with a 429 or 5xx response, honoring ctx
+// cancellation while waiting between attempts. Pass nil to disable retries.
+func (c *Client) SetRetryPolicy(rp *RetryPolicy) {
+	c.retry = rp
+}
+
 // Domains gets the list of domains (sans DNS records), billing, and other user
 // information for the logged-in user
 func (c *Client) Domains(ctx context.Context) ([]*Domain, error) {
@@ -282,12 +555,12 @@ func (c *Client) Domains(ctx context.Context) ([]*Domain, error) {
 		hoverResp
 		Domains []*Domain `json:"domains"`
 	}{}
-	code, err := c.do(ctx, dr, "GET", defaultURL+"/domains", nil)
+	code, retryAfter, err := c.do(ctx, dr, "GET", defaultURL+"/domains", nil)
 	if err != nil {
 		return nil, err
 	}
 	if !dr.Succeeded {
-		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return dr.Domains, nil
 }
@@ -295,12 +568,12 @@ func (c *Client) Domains(ctx context.Context) ([]*Domain, error) {
 // DNS gets the list of DNS domain information
 func (c *Client) DNS(ctx context.Context) ([]*DNSDomain, error) {
 	dr := &dnsDomainsResp{}
-	code, err := c.do(ctx, dr, "GET", defaultURL+"/dns", nil)
+	code, retryAfter, err := c.do(ctx, dr, "GET", defaultURL+"/dns", nil)
 	if err != nil {
 		return nil, err
 	}
 	if !dr.Succeeded {
-		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return dr.DNSDomains, nil
 }
@@ -314,13 +587,13 @@ func (c *Client) GetDomain(ctx context.Context, domainID DomainID) (*Domain, err
 		hoverResp
 		Domain *Domain `json:"domain"`
 	}{}
-	code, err := c.do(ctx, dr, "GET", fmt.Sprintf("%s/domains/%s", defaultURL, string(domainID)), nil)
+	code, retryAfter, err := c.do(ctx, dr, "GET", fmt.Sprintf("%s/domains/%s", defaultURL, string(domainID)), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if !dr.Succeeded {
-		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return dr.Domain, nil
 }
@@ -336,12 +609,12 @@ func (c *Client) GetDNSDomains(ctx context.Context, domainID DomainID) ([]*DNSDo
 		return nil, errors.New("empty domainID")
 	}
 	dr := &dnsDomainsResp{}
-	code, err := c.do(ctx, dr, "GET", fmt.Sprintf("%s/domains/%s/dns", defaultURL, string(domainID)), nil)
+	code, retryAfter, err := c.do(ctx, dr, "GET", fmt.Sprintf("%s/domains/%s/dns", defaultURL, string(domainID)), nil)
 	if err != nil {
 		return nil, err
 	}
 	if !dr.Succeeded {
-		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return nil, &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return dr.DNSDomains, nil
 }
@@ -369,12 +642,12 @@ func (c *Client) AddDNSRecord(ctx context.Context, domainID DomainID, rec *NewDN
 	v.Set("content", rec.Content)
 	v.Set("ttl", strconv.Itoa(int(rec.TTL/time.Second)))
 	dr := &hoverResp{}
-	code, err := c.do(ctx, dr, "POST", fmt.Sprintf("%s/domains/%s/dns?%s", defaultURL, domainID, v.Encode()), nil)
+	code, retryAfter, err := c.do(ctx, dr, "POST", fmt.Sprintf("%s/domains/%s/dns?%s", defaultURL, domainID, v.Encode()), nil)
 	if err != nil {
 		return err
 	}
 	if !dr.Succeeded {
-		return &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return nil
 }
@@ -382,33 +655,294 @@ func (c *Client) AddDNSRecord(ctx context.Context, domainID DomainID, rec *NewDN
 // DeleteDNSRecord deletes the DNS record specifiedy by the given DNSRecordID
 func (c *Client) DeleteDNSRecord(ctx context.Context, dnsID DNSRecordID) error {
 	dr := &hoverResp{}
-	code, err := c.do(ctx, dr, "DELETE", fmt.Sprintf("%s/dns/%s", defaultURL, string(dnsID)), nil)
+	code, retryAfter, err := c.do(ctx, dr, "DELETE", fmt.Sprintf("%s/dns/%s", defaultURL, string(dnsID)), nil)
 	if err != nil {
 		return err
 	}
 	if !dr.Succeeded {
-		return &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error}
+		return &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
 	}
 	return nil
 }
 
-// do performs a HTTP request given the data, unmarshals the returned JSON into
-// obj, and returns the HTTP status code of the response and any errors, if any,
-// that occur along the way.
-func (c *Client) do(ctx context.Context, obj interface{}, method, urlStr string, r io.Reader) (int, error) {
-	req, err := http.NewRequest(method, urlStr, r)
+// UpdateDNSRecord updates the Content, TTL, Type, and Name of the DNS record
+// specified by the given DNSRecordID in place. If the Hover API responds that
+// it doesn't support updating records in place (a 405 Method Not Allowed),
+// UpdateDNSRecord falls back to deleting the old record and adding rec as a
+// new one under the given domainID. That fallback is the reason
+// UpdateDNSRecord takes a domainID parameter at all: AddDNSRecord requires
+// one.
+func (c *Client) UpdateDNSRecord(ctx context.Context, domainID DomainID, dnsID DNSRecordID, rec *NewDNSRecord) error {
+	if dnsID == "" {
+		return errors.New("empty dnsID")
+	}
+	if rec.Content == "" {
+		return errors.New("Content can't be empty")
+	}
+	v := url.Values{}
+	v.Set("type", string(rec.Type))
+	v.Set("name", rec.Name)
+	v.Set("content", rec.Content)
+	v.Set("ttl", strconv.Itoa(int(rec.TTL/time.Second)))
+	dr := &hoverResp{}
+	code, retryAfter, err := c.do(ctx, dr, "PUT", fmt.Sprintf("%s/dns/%s?%s", defaultURL, dnsID, v.Encode()), nil)
+	var ae *APIError
+	if errors.As(err, &ae) && ae.StatusCode == http.StatusMethodNotAllowed {
+		if domainID == "" {
+			return errors.New("empty domainID needed for delete+add fallback")
+		}
+		if err := c.DeleteDNSRecord(ctx, dnsID); err != nil {
+			return err
+		}
+		return c.AddDNSRecord(ctx, domainID, rec)
+	}
+	if err != nil {
+		return err
+	}
+	if !dr.Succeeded {
+		return &APIError{StatusCode: code, ErrorCode: dr.ErrorCode, ErrorMsg: dr.Error, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// UpsertDNSRecord finds the DNS record under domainID matching rec's Type and
+// Name and updates it with rec's Content and TTL. If no matching record
+// exists, it adds rec as a new record instead. This is the common operation
+// wanted by dynamic DNS and cert renewal automation, which don't want to
+// track DNSRecordIDs themselves.
+func (c *Client) UpsertDNSRecord(ctx context.Context, domainID DomainID, rec *NewDNSRecord) error {
+	if domainID == "" {
+		return errors.New("empty domainID")
+	}
+	dnsDomains, err := c.GetDNSDomains(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	for _, dnsDomain := range dnsDomains {
+		for _, entry := range dnsDomain.Entries {
+			if entry.Type == rec.Type && entry.Name == rec.Name {
+				return c.UpdateDNSRecord(ctx, domainID, entry.ID, rec)
+			}
+		}
+	}
+	return c.AddDNSRecord(ctx, domainID, rec)
+}
+
+// ExportZone serializes the DNS records of domainID into RFC 1035
+// master-file ("BIND zonefile") format, suitable for backing up or migrating
+// a domain's DNS configuration. Each exported record carries a "hover-id"
+// comment so that a later ImportZone with ImportOptions.PreserveIDs can
+// match records across the round trip even if their Name or Content changed.
+func (c *Client) ExportZone(ctx context.Context, domainID DomainID) ([]byte, error) {
+	if domainID == "" {
+		return nil, errors.New("empty domainID")
+	}
+	dnsDomains, err := c.GetDNSDomains(ctx, domainID)
 	if err != nil {
-		return -1, err
+		return nil, err
+	}
+	if len(dnsDomains) == 0 {
+		return nil, fmt.Errorf("no DNSDomain found for domain %s", domainID)
+	}
+	dnsDomain := dnsDomains[0]
+
+	zone := zonefile.Zone{
+		Origin:     dnsDomain.DomainName,
+		DefaultTTL: time.Hour,
+	}
+	for _, entry := range dnsDomain.Entries {
+		zone.Records = append(zone.Records, zonefile.Record{
+			ID:      string(entry.ID),
+			Name:    entry.Name,
+			TTL:     time.Duration(entry.TTL),
+			Type:    string(entry.Type),
+			Content: entry.Content,
+		})
+	}
+	return zonefile.Marshal(zone)
+}
+
+// ImportOptions controls how ImportZone reconciles a parsed zonefile against
+// a domain's existing DNS records.
+type ImportOptions struct {
+	// DryRun computes the ImportResult without making any mutating API
+	// calls. ImportZone still has to read the domain's existing DNS
+	// records to compute the diff.
+	DryRun bool
+	// DeleteExtra deletes existing records that have no corresponding record
+	// in the imported zone. Without it, ImportZone only adds and updates.
+	DeleteExtra bool
+	// PreserveIDs matches imported records against existing ones by the
+	// "hover-id" comment ExportZone writes, instead of by (Type, Name). This
+	// lets a round-tripped zonefile rename or re-point a record in place
+	// rather than deleting and re-adding it.
+	PreserveIDs bool
+}
+
+// ImportResult reports what ImportZone did (or, under ImportOptions.DryRun,
+// would have done).
+type ImportResult struct {
+	Added     int
+	Updated   int
+	Deleted   int
+	Unchanged int
+}
+
+// ImportZone parses a zonefile from r and reconciles it against the existing
+// DNS records of domainID, according to opts.
+func (c *Client) ImportZone(ctx context.Context, domainID DomainID, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	var result ImportResult
+	if domainID == "" {
+		return result, errors.New("empty domainID")
 	}
-	req.AddCookie(c.cook)
-	resp, err := ctxhttp.Do(ctx, c.hc, req)
+	zone, err := zonefile.Parse(r)
 	if err != nil {
-		return -1, err
+		return result, err
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	dnsDomains, err := c.GetDNSDomains(ctx, domainID)
 	if err != nil {
-		return -1, err
+		return result, err
+	}
+	var existing []*DNSRecord
+	if len(dnsDomains) > 0 {
+		existing = dnsDomains[0].Entries
+	}
+	matched := make(map[DNSRecordID]bool, len(existing))
+
+	for _, rzr := range zone.Records {
+		match := findMatch(existing, rzr, opts.PreserveIDs)
+		rec := &NewDNSRecord{
+			Type:    RecordType(rzr.Type),
+			Name:    rzr.Name,
+			Content: rzr.Content,
+			TTL:     rzr.TTL,
+		}
+		if match == nil {
+			result.Added++
+			if !opts.DryRun {
+				if err := c.AddDNSRecord(ctx, domainID, rec); err != nil {
+					return result, err
+				}
+			}
+			continue
+		}
+		matched[match.ID] = true
+		if match.Name == rzr.Name && match.Content == rzr.Content && time.Duration(match.TTL) == rzr.TTL {
+			result.Unchanged++
+			continue
+		}
+		result.Updated++
+		if !opts.DryRun {
+			if err := c.UpdateDNSRecord(ctx, domainID, match.ID, rec); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if opts.DeleteExtra {
+		for _, entry := range existing {
+			if matched[entry.ID] {
+				continue
+			}
+			result.Deleted++
+			if !opts.DryRun {
+				if err := c.DeleteDNSRecord(ctx, entry.ID); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// findMatch finds the existing DNSRecord that rec should update, if any.
+func findMatch(existing []*DNSRecord, rec zonefile.Record, byID bool) *DNSRecord {
+	for _, entry := range existing {
+		if byID && rec.ID != "" {
+			if string(entry.ID) == rec.ID {
+				return entry
+			}
+			continue
+		}
+		if string(entry.Type) == rec.Type && entry.Name == rec.Name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// do performs a HTTP request given the data, unmarshals the returned JSON into
+// obj, and returns the HTTP status code of the response, any Retry-After
+// duration the response specified, and any errors, if any, that occur along
+// the way. A non-2xx response is always turned into an *APIError, even if
+// its body isn't Hover's usual JSON envelope (a gateway or CDN in front of
+// the API can return an empty body or an HTML error page on a 429, 404, or
+// 5xx), so errors.Is(err, ErrRateLimited) and friends work regardless of
+// what produced the error. If c has a RetryPolicy set, GET and DELETE
+// requests made with a nil body are automatically retried on 429 and 5xx
+// responses, honoring ctx cancellation between attempts; POST and PUT
+// requests are never retried.
+func (c *Client) do(ctx context.Context, obj interface{}, method, urlStr string, r io.Reader) (int, time.Duration, error) {
+	canRetry := r == nil && (method == "GET" || method == "DELETE")
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest(method, urlStr, r)
+		if err != nil {
+			return -1, 0, err
+		}
+		req.AddCookie(c.cook)
+		resp, err := ctxhttp.Do(ctx, c.hc, req)
+		if err != nil {
+			return -1, 0, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return -1, 0, err
+		}
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !canRetry || c.retry == nil || !retryable || attempt >= c.retry.MaxAttempts {
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				// The body may not be the Hover JSON envelope at all (an
+				// intervening gateway's HTML error page, an empty 404, ...),
+				// so parse it best-effort and still return a typed APIError.
+				er := &hoverResp{}
+				json.Unmarshal(body, er)
+				return resp.StatusCode, retryAfter, &APIError{StatusCode: resp.StatusCode, ErrorCode: er.ErrorCode, ErrorMsg: er.Error, RetryAfter: retryAfter}
+			}
+			return resp.StatusCode, retryAfter, json.Unmarshal(body, obj)
+		}
+
+		backoff := c.retry.Backoff
+		if backoff == nil {
+			backoff = ExponentialBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return resp.StatusCode, retryAfter, ctx.Err()
+		case <-time.After(backoff(attempt, retryAfter)):
+		}
+	}
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value, which may be
+// either a number of seconds or an HTTP-date, into a time.Duration. It
+// returns zero if h is empty or unparseable.
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
 	}
-	return resp.StatusCode, json.Unmarshal(body, obj)
+	return 0
 }
@@ -0,0 +1,188 @@
+package hover
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2019-06-05"`, time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var d Date
+		if err := json.Unmarshal([]byte(tt.in), &d); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", tt.in, err)
+			continue
+		}
+		if !d.Time.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, d.Time, tt.want)
+		}
+	}
+}
+
+func TestDateUnmarshalJSONInvalid(t *testing.T) {
+	var d Date
+	if err := json.Unmarshal([]byte(`"not-a-date"`), &d); err == nil {
+		t.Error("Unmarshal of an invalid date string returned no error")
+	}
+}
+
+func TestDateMarshalJSONRoundTrip(t *testing.T) {
+	d := Date{time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC)}
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	if string(b) != `"2019-06-05"` {
+		t.Errorf("Marshal(%v) = %s, want %s", d, b, `"2019-06-05"`)
+	}
+	var got Date
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) returned error: %s", b, err)
+	}
+	if !got.Time.Equal(d.Time) {
+		t.Errorf("round-tripped Date = %v, want %v", got.Time, d.Time)
+	}
+}
+
+func TestDateMarshalJSONZero(t *testing.T) {
+	var d Date
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	if string(b) != "null" {
+		t.Errorf("Marshal(zero Date) = %s, want null", b)
+	}
+}
+
+func TestYearMonthUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2019/06"`, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var ym YearMonth
+		if err := json.Unmarshal([]byte(tt.in), &ym); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %s", tt.in, err)
+			continue
+		}
+		if !ym.Time.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, ym.Time, tt.want)
+		}
+	}
+}
+
+func TestYearMonthMarshalJSONRoundTrip(t *testing.T) {
+	ym := YearMonth{time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)}
+	b, err := json.Marshal(ym)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	if string(b) != `"2019/06"` {
+		t.Errorf("Marshal(%v) = %s, want %s", ym, b, `"2019/06"`)
+	}
+	var got YearMonth
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) returned error: %s", b, err)
+	}
+	if !got.Time.Equal(ym.Time) {
+		t.Errorf("round-tripped YearMonth = %v, want %v", got.Time, ym.Time)
+	}
+}
+
+func TestTTLMarshalJSONRoundTrip(t *testing.T) {
+	ttl := TTL(900 * time.Second)
+	b, err := json.Marshal(ttl)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	if string(b) != "900" {
+		t.Errorf("Marshal(%v) = %s, want 900", ttl, b)
+	}
+	var got TTL
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) returned error: %s", b, err)
+	}
+	if got != ttl {
+		t.Errorf("round-tripped TTL = %v, want %v", got, ttl)
+	}
+}
+
+func TestRecordTypeMarshalJSONRoundTrip(t *testing.T) {
+	rt := TXT
+	b, err := json.Marshal(rt)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	if string(b) != `"TXT"` {
+		t.Errorf("Marshal(%v) = %s, want %s", rt, b, `"TXT"`)
+	}
+	var got RecordType
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) returned error: %s", b, err)
+	}
+	if got != rt {
+		t.Errorf("round-tripped RecordType = %v, want %v", got, rt)
+	}
+}
+
+// domainsFixture is a recorded (and trimmed) example of what the Hover API
+// returns from GET /domains, including the un-registered-domain case where
+// renewal_date and display_date come back as null.
+const domainsFixture = `{
+	"succeeded": true,
+	"domains": [
+		{
+			"id": "dom123",
+			"status": "active",
+			"domain_name": "example.com",
+			"renewal_date": "2020-01-15",
+			"display_date": "2020-01-15",
+			"registered_date": "2010-01-15",
+			"renewable": true,
+			"auto_renew": true
+		},
+		{
+			"id": "dom456",
+			"status": "pending_registration",
+			"domain_name": "example.org",
+			"renewal_date": null,
+			"display_date": null,
+			"registered_date": null,
+			"renewable": false,
+			"auto_renew": false
+		}
+	]
+}`
+
+func TestDomainsFixtureDecodesCleanly(t *testing.T) {
+	dr := &struct {
+		hoverResp
+		Domains []*Domain `json:"domains"`
+	}{}
+	if err := json.Unmarshal([]byte(domainsFixture), dr); err != nil {
+		t.Fatalf("Unmarshal returned error: %s", err)
+	}
+	if len(dr.Domains) != 2 {
+		t.Fatalf("got %d domains, want 2", len(dr.Domains))
+	}
+	registered, unregistered := dr.Domains[0], dr.Domains[1]
+	if registered.RenewalDate.Time.IsZero() {
+		t.Error("registered domain's RenewalDate is zero, want a parsed date")
+	}
+	if !unregistered.RenewalDate.Time.IsZero() {
+		t.Errorf("unregistered domain's RenewalDate = %v, want zero", unregistered.RenewalDate.Time)
+	}
+}
@@ -0,0 +1,287 @@
+// Package zonefile serializes and parses DNS zone data in the RFC 1035
+// master-file ("BIND zonefile") format, so that callers of hover.Client can
+// back up or migrate the DNS records of a domain.
+//
+// The format this package produces and consumes is a practical subset of
+// RFC 1035: $ORIGIN and $TTL directives, a single parenthesized SOA record,
+// and one resource record per line. It's meant to round-trip zones this
+// package itself produced; it is not a general-purpose BIND zonefile parser.
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SOA holds the metadata a zone's SOA record carries, beyond the resource
+// records themselves.
+type SOA struct {
+	// MName is the primary nameserver for the zone.
+	MName string
+	// RName is the responsible party's mailbox, in zonefile form (a dot in
+	// place of the usual '@').
+	RName string
+
+	Serial  uint32
+	Refresh time.Duration
+	Retry   time.Duration
+	Expire  time.Duration
+	Minimum time.Duration
+}
+
+// Record is a single resource record in a Zone. Content holds the record's
+// RDATA exactly as hover.DNSRecord.Content does, so converting between the
+// two is a matter of copying fields.
+type Record struct {
+	// ID is the hover.DNSRecordID this record was exported with, if any. It
+	// round-trips through Marshal/Parse as a "hover-id" comment so that
+	// ImportOptions.PreserveIDs can match records across a re-import.
+	ID      string
+	Name    string
+	TTL     time.Duration
+	Type    string
+	Content string
+}
+
+// Zone is a parsed (or to-be-serialized) DNS zone: an origin, its SOA
+// metadata and default TTL, and its resource records.
+type Zone struct {
+	Origin     string
+	DefaultTTL time.Duration
+	SOA        SOA
+	Records    []Record
+}
+
+// Marshal serializes zone into RFC 1035 master-file format.
+func Marshal(zone Zone) ([]byte, error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "$ORIGIN %s.\n", strings.TrimSuffix(zone.Origin, "."))
+	fmt.Fprintf(&buf, "$TTL %d\n\n", int(zone.DefaultTTL/time.Second))
+
+	fmt.Fprintf(&buf, "@\tIN\tSOA\t%s.\t%s. (\n", trimDot(zone.SOA.MName), trimDot(zone.SOA.RName))
+	fmt.Fprintf(&buf, "\t\t\t%d ; serial\n", zone.SOA.Serial)
+	fmt.Fprintf(&buf, "\t\t\t%d ; refresh\n", int(zone.SOA.Refresh/time.Second))
+	fmt.Fprintf(&buf, "\t\t\t%d ; retry\n", int(zone.SOA.Retry/time.Second))
+	fmt.Fprintf(&buf, "\t\t\t%d ; expire\n", int(zone.SOA.Expire/time.Second))
+	fmt.Fprintf(&buf, "\t\t\t%d ) ; minimum\n\n", int(zone.SOA.Minimum/time.Second))
+
+	for _, rec := range zone.Records {
+		name := rec.Name
+		if name == "" {
+			name = "@"
+		}
+		content := rec.Content
+		if strings.EqualFold(rec.Type, "TXT") {
+			content = quoteTXT(content)
+		}
+		line := fmt.Sprintf("%s\t%d\tIN\t%s\t%s", name, int(rec.TTL/time.Second), rec.Type, content)
+		if rec.ID != "" {
+			line += fmt.Sprintf(" ; hover-id=%s", rec.ID)
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return []byte(buf.String()), nil
+}
+
+// Parse reads a zone previously produced by Marshal (or hand-written in the
+// same practical subset of the format) from r.
+func Parse(r io.Reader) (Zone, error) {
+	var zone Zone
+	scanner := bufio.NewScanner(r)
+
+	var pending, pendingID string
+	inParens := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		line, id := extractHoverID(line)
+		if id != "" {
+			pendingID = id
+		}
+		line = stripComment(line)
+		if strings.TrimSpace(line) == "" && !inParens {
+			continue
+		}
+
+		pending += " " + line
+		opens := strings.Count(line, "(")
+		closes := strings.Count(line, ")")
+		if inParens {
+			if closes > 0 {
+				inParens = false
+			} else {
+				continue
+			}
+		} else if opens > closes {
+			inParens = true
+			continue
+		}
+
+		if err := parseLogicalLine(&zone, strings.TrimSpace(pending), pendingID); err != nil {
+			return zone, err
+		}
+		pending = ""
+		pendingID = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return zone, err
+	}
+	if inParens {
+		return zone, fmt.Errorf("zonefile: unterminated parenthesized record")
+	}
+	return zone, nil
+}
+
+func parseLogicalLine(zone *Zone, line, id string) error {
+	if line == "" {
+		return nil
+	}
+	fields := strings.Fields(line)
+	switch strings.ToUpper(fields[0]) {
+	case "$ORIGIN":
+		if len(fields) != 2 {
+			return fmt.Errorf("zonefile: malformed $ORIGIN line %q", line)
+		}
+		zone.Origin = trimDot(fields[1])
+		return nil
+	case "$TTL":
+		if len(fields) != 2 {
+			return fmt.Errorf("zonefile: malformed $TTL line %q", line)
+		}
+		secs, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return fmt.Errorf("zonefile: malformed $TTL line %q: %s", line, err)
+		}
+		zone.DefaultTTL = time.Duration(secs) * time.Second
+		return nil
+	}
+
+	i := 0
+	name := fields[i]
+	i++
+	ttl := zone.DefaultTTL
+	if i < len(fields) {
+		if secs, err := strconv.Atoi(fields[i]); err == nil {
+			ttl = time.Duration(secs) * time.Second
+			i++
+		}
+	}
+	if i < len(fields) && strings.EqualFold(fields[i], "IN") {
+		i++
+	}
+	if i >= len(fields) {
+		return fmt.Errorf("zonefile: malformed record line %q", line)
+	}
+	typ := strings.ToUpper(fields[i])
+	i++
+
+	if typ == "SOA" {
+		return parseSOA(zone, fields[i:])
+	}
+
+	content := strings.Join(fields[i:], " ")
+	if typ == "TXT" {
+		content = unquoteTXT(content)
+	}
+	if name == "@" {
+		name = ""
+	}
+	zone.Records = append(zone.Records, Record{
+		ID:      id,
+		Name:    name,
+		TTL:     ttl,
+		Type:    typ,
+		Content: content,
+	})
+	return nil
+}
+
+// parseSOA parses the fields of a (possibly parenthesized, multi-line) SOA
+// record. The bare "(" and ")" tokens used to span an SOA record across
+// lines carry no data of their own, so they're dropped before the remaining
+// fields are read positionally.
+func parseSOA(zone *Zone, fields []string) error {
+	var names, nums []string
+	for _, f := range fields {
+		switch {
+		case f == "(" || f == ")":
+			continue
+		case len(names) < 2:
+			names = append(names, trimDot(f))
+		default:
+			nums = append(nums, strings.TrimSuffix(f, ")"))
+		}
+	}
+	if len(names) < 2 || len(nums) < 5 {
+		return fmt.Errorf("zonefile: malformed SOA record: %v", fields)
+	}
+	zone.SOA.MName, zone.SOA.RName = names[0], names[1]
+	serial, err := strconv.ParseUint(nums[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("zonefile: malformed SOA serial %q: %s", nums[0], err)
+	}
+	zone.SOA.Serial = uint32(serial)
+
+	durs := make([]time.Duration, 4)
+	for idx, n := range nums[1:5] {
+		secs, err := strconv.Atoi(n)
+		if err != nil {
+			return fmt.Errorf("zonefile: malformed SOA field %q: %s", n, err)
+		}
+		durs[idx] = time.Duration(secs) * time.Second
+	}
+	zone.SOA.Refresh, zone.SOA.Retry, zone.SOA.Expire, zone.SOA.Minimum = durs[0], durs[1], durs[2], durs[3]
+	return nil
+}
+
+// stripComment removes a trailing "; ..." comment from line, ignoring any
+// ';' that appears inside a double-quoted string.
+func stripComment(line string) string {
+	inQuotes := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// extractHoverID pulls a "; hover-id=VALUE" comment out of line, returning
+// the line with it removed and the extracted value, if any.
+func extractHoverID(line string) (string, string) {
+	idx := strings.Index(line, "; hover-id=")
+	if idx < 0 {
+		return line, ""
+	}
+	rest := line[idx+len("; hover-id="):]
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return line, ""
+	}
+	return line[:idx], fields[0]
+}
+
+func quoteTXT(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func unquoteTXT(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return strings.ReplaceAll(s, `\"`, `"`)
+}
+
+func trimDot(s string) string {
+	return strings.TrimSuffix(s, ".")
+}
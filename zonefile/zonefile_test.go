@@ -0,0 +1,75 @@
+package zonefile
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testZone() Zone {
+	return Zone{
+		Origin:     "example.com",
+		DefaultTTL: 3600 * time.Second,
+		SOA: SOA{
+			MName:   "ns1.hover.com",
+			RName:   "hostmaster.example.com",
+			Serial:  2019060500,
+			Refresh: time.Hour,
+			Retry:   10 * time.Minute,
+			Expire:  14 * 24 * time.Hour,
+			Minimum: time.Hour,
+		},
+		Records: []Record{
+			{ID: "dns1", Name: "www", TTL: 300 * time.Second, Type: "A", Content: "192.0.2.1"},
+			{Name: "", TTL: 3600 * time.Second, Type: "MX", Content: "10 mail.example.com."},
+			{Name: "_acme-challenge", TTL: 300 * time.Second, Type: "TXT", Content: `a "quoted" value`},
+		},
+	}
+}
+
+func TestMarshalParseRoundTrip(t *testing.T) {
+	zone := testZone()
+	b, err := Marshal(zone)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+
+	got, err := Parse(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatalf("Parse returned error: %s\nzonefile was:\n%s", err, b)
+	}
+
+	if got.Origin != zone.Origin {
+		t.Errorf("Origin = %q, want %q", got.Origin, zone.Origin)
+	}
+	if got.DefaultTTL != zone.DefaultTTL {
+		t.Errorf("DefaultTTL = %v, want %v", got.DefaultTTL, zone.DefaultTTL)
+	}
+	if got.SOA != zone.SOA {
+		t.Errorf("SOA = %+v, want %+v", got.SOA, zone.SOA)
+	}
+	if len(got.Records) != len(zone.Records) {
+		t.Fatalf("got %d records, want %d", len(got.Records), len(zone.Records))
+	}
+	for i, want := range zone.Records {
+		got := got.Records[i]
+		if got != want {
+			t.Errorf("record %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestParsePreservesHoverID(t *testing.T) {
+	zone := testZone()
+	b, err := Marshal(zone)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %s", err)
+	}
+	got, err := Parse(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatalf("Parse returned error: %s", err)
+	}
+	if got.Records[0].ID != "dns1" {
+		t.Errorf("Records[0].ID = %q, want %q", got.Records[0].ID, "dns1")
+	}
+}